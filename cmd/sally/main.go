@@ -0,0 +1,83 @@
+// Command sally serves vanity import paths, godoc redirects, and
+// optional hot-reloading admin endpoints, as configured by a
+// sallyhttp.Config.
+package main // import "go.uber.org/sally/cmd/sally"
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.uber.org/sally/sallyhttp"
+	"go.uber.org/zap"
+)
+
+func main() {
+	yml := flag.String("yml", "sally.yaml", "path or URI (file://, http(s)://, consul://, etcd://) to read config from")
+	port := flag.Int("port", 8080, "port to listen and serve on")
+	watch := flag.Bool("watch", false, "watch the configuration source for changes and reload without restarting")
+	adminAddr := flag.String("admin-addr", "", "address to serve /-/reload and /metrics on; defaults to serving them alongside vanity redirects on -port")
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to set up logger: %v", err)
+		os.Exit(1)
+	}
+
+	source, err := sallyhttp.NewConfigSource(*yml, logger)
+	if err != nil {
+		logger.Fatal("Failed to set up configuration source", zap.String("source", *yml), zap.Error(err))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger.Debug("Loading configuration", zap.String("source", *yml))
+
+	config, err := source.Load(ctx)
+	if err != nil {
+		logger.Fatal("Failed to load configuration", zap.String("source", *yml), zap.Error(err))
+	}
+
+	logger.Info("Loaded configuration", zap.Object("config", config))
+	handler := sallyhttp.CreateHandler(config,
+		sallyhttp.WithLogger(logger),
+		sallyhttp.WithConfigSource(source),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+
+	adminMux := mux
+	if *adminAddr != "" {
+		adminMux = http.NewServeMux()
+		go func() {
+			logger.Info("Starting admin HTTP server", zap.String("addr", *adminAddr))
+			if err := http.ListenAndServe(*adminAddr, adminMux); err != nil {
+				logger.Fatal("Admin server stopped", zap.Error(err))
+			}
+		}()
+	}
+	adminMux.Handle("/metrics", handler.MetricsHandler())
+
+	if *watch {
+		adminMux.Handle("/-/reload", handler.ReloadHandler())
+
+		watcher := sallyhttp.NewConfigWatcher(handler, logger)
+		go func() {
+			if err := watcher.Watch(ctx); err != nil && err != context.Canceled {
+				logger.Error("Configuration watcher stopped", zap.Error(err))
+			}
+		}()
+	}
+
+	addr := fmt.Sprintf(":%d", *port)
+	logger.Info("Starting HTTP server", zap.String("addr", addr))
+
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		logger.Fatal("Server stopped", zap.Error(err))
+	}
+}
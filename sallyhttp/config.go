@@ -0,0 +1,204 @@
+// Package sallyhttp implements the vanity import-path handler that
+// backs the sally command, for embedding into other HTTP servers.
+package sallyhttp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	yaml "gopkg.in/yaml.v3"
+)
+
+const (
+	_defaultGodocServer = "pkg.go.dev"
+	_defaultBranch      = "master"
+)
+
+// Config represents the structure of the yaml file
+type Config struct {
+	URL      string             `yaml:"url"`
+	Packages map[string]Package `yaml:"packages"`
+	Godoc    GodocConfig        `yaml:"godoc"`
+	Admin    AdminConfig        `yaml:"admin"`
+}
+
+func (cfg *Config) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("url", cfg.URL)
+	if !cfg.Godoc.empty() {
+		enc.AddObject("godoc", &cfg.Godoc)
+	}
+	if !cfg.Admin.empty() {
+		enc.AddObject("admin", &cfg.Admin)
+	}
+	return enc.AddObject("packages", packageGroup(cfg.Packages))
+}
+
+// AdminConfig configures the administrative endpoints Sally exposes
+// alongside its vanity redirects, such as /-/reload.
+type AdminConfig struct {
+	// ReloadToken, if set, must be presented as a bearer token on
+	// requests to /-/reload. Leave empty to allow unauthenticated
+	// reloads.
+	ReloadToken string `yaml:"reload_token"`
+}
+
+func (ac *AdminConfig) empty() bool {
+	return ac.ReloadToken == ""
+}
+
+func (ac *AdminConfig) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddBool("reloadTokenSet", ac.ReloadToken != "")
+	return nil
+}
+
+// GodocConfig is the configuration for the godoc documentation server.
+type GodocConfig struct {
+	Host string `yaml:"host"`
+}
+
+func (gc *GodocConfig) empty() bool {
+	return gc.Host == ""
+}
+
+func (gc *GodocConfig) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("host", gc.Host)
+	return nil
+}
+
+type packageGroup map[string]Package
+
+func (ps packageGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for name, p := range ps {
+		if err := enc.AddObject(name, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Package details the options available for each repo
+type Package struct {
+	Repo   string `yaml:"repo"`
+	Branch string `yaml:"branch"`
+	URL    string `yaml:"url"`
+
+	Desc string `yaml:"description"` // plain text only
+}
+
+func (pkg Package) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("repo", pkg.Repo)
+	enc.AddString("branch", pkg.Branch)
+	if len(pkg.URL) > 0 {
+		enc.AddString("url", pkg.URL)
+	}
+	if len(pkg.Desc) > 0 {
+		enc.AddString("description", pkg.Desc)
+	}
+	return nil
+}
+
+// Parse takes a path to a yaml file and produces a parsed Config
+func Parse(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(data)
+}
+
+// parseConfig parses raw YAML into a Config, applying defaults and
+// validating package patterns.
+//
+// This is the shared implementation behind Parse and the non-file
+// ConfigSource implementations, which read their YAML from HTTP,
+// Consul, or etcd rather than the local filesystem.
+func parseConfig(data []byte) (*Config, error) {
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	if c.Godoc.Host == "" {
+		c.Godoc.Host = _defaultGodocServer
+	} else {
+		host := c.Godoc.Host
+		host = strings.TrimPrefix(host, "https://")
+		host = strings.TrimPrefix(host, "http://")
+		host = strings.TrimSuffix(host, "/")
+		c.Godoc.Host = host
+	}
+
+	// set default branch
+	for v, p := range c.Packages {
+		if p.Branch == "" {
+			p.Branch = _defaultBranch
+			c.Packages[v] = p
+		}
+	}
+
+	if err := validatePatterns(c.Packages); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// validatePatterns checks that the parametric package paths in packages
+// (e.g. "mytools/{user}/{repo}") are well-formed and don't conflict with
+// each other or with literal package paths, without otherwise mutating
+// packages.
+func validatePatterns(packages map[string]Package) error {
+	var patterns paramTree[struct{}]
+	var patternNames []string
+	for name := range packages {
+		if !isPattern(name) {
+			continue
+		}
+		if err := patterns.Set(name, struct{}{}); err != nil {
+			return fmt.Errorf("invalid package pattern: %w", err)
+		}
+		patternNames = append(patternNames, name)
+	}
+
+	// A literal package cascades, via pathTree's Lookup, to every path
+	// beneath it - including paths a templated package further down
+	// would otherwise have matched. Since the literal package tree is
+	// always consulted first, that silently shadows the pattern for
+	// every request under it, so reject the overlap up front instead.
+	for name := range packages {
+		if isPattern(name) {
+			continue
+		}
+		for _, pattern := range patternNames {
+			if literalShadowsPattern(name, pattern) {
+				return fmt.Errorf("package %q shadows pattern %q: a literal package can't be an ancestor of a templated one", name, pattern)
+			}
+		}
+	}
+
+	return nil
+}
+
+// literalShadowsPattern reports whether the literal package path
+// literal is an ancestor of, or equal to, every path pattern can
+// match - i.e. whether pattern's segments up to len(literal)'s
+// components are all literal and equal to literal's own components.
+func literalShadowsPattern(literal, pattern string) bool {
+	segs, err := compilePattern(pattern)
+	if err != nil {
+		return false
+	}
+
+	parts := strings.Split(literal, "/")
+	if len(parts) > len(segs) {
+		return false
+	}
+	for i, part := range parts {
+		if segs[i].kind != segmentLiteral || segs[i].literal != part {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package sallyhttp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ConfigSource loads a Sally configuration from a backing store - a
+// local file, an HTTP(S) endpoint, or a Consul/etcd key - and can watch
+// that store for changes.
+type ConfigSource interface {
+	// Load reads, parses, and validates the configuration once.
+	Load(ctx context.Context) (*Config, error)
+
+	// Watch returns a channel that receives a new Config every time the
+	// backing configuration changes. Implementations log and skip
+	// configurations that fail to parse or validate, leaving the
+	// previous configuration as the last known good value.
+	//
+	// The returned channel is closed when ctx is canceled.
+	Watch(ctx context.Context) (<-chan *Config, error)
+}
+
+// NewConfigSource parses uri and returns the ConfigSource it describes.
+//
+// uri may be a plain filesystem path, equivalent to a file:// URI, or a
+// URI with one of the following schemes:
+//
+//	file://path/to/sally.yaml
+//	http(s)://host/path/to/sally.yaml
+//	consul://host:port/path/to/key
+//	etcd://host:port/path/to/key
+func NewConfigSource(uri string, logger *zap.Logger) (ConfigSource, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme == "" {
+		return NewFileConfigSource(uri, logger), nil
+	}
+
+	switch u.Scheme {
+	case "file":
+		// url.Parse treats the first path segment after "file://" as
+		// the host (e.g. "file://path/to/sally.yaml" puts "path" in
+		// u.Host and "/to/sally.yaml" in u.Path), so both must be
+		// rejoined to recover the path the doc comment above promises.
+		return NewFileConfigSource(u.Host+u.Path, logger), nil
+	case "http", "https":
+		return NewHTTPConfigSource(uri, logger), nil
+	case "consul":
+		return NewConsulConfigSource(u.Host, strings.TrimPrefix(u.Path, "/"), logger)
+	case "etcd":
+		return NewEtcdConfigSource(u.Host, strings.TrimPrefix(u.Path, "/"), logger)
+	default:
+		return nil, fmt.Errorf("unsupported config source scheme %q", u.Scheme)
+	}
+}
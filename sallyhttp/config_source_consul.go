@@ -0,0 +1,87 @@
+package sallyhttp
+
+import (
+	"context"
+	"fmt"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"go.uber.org/zap"
+)
+
+// ConsulConfigSource loads a Config from a key in Consul's KV store and
+// watches it for changes using Consul's blocking queries.
+type ConsulConfigSource struct {
+	client *consulapi.Client
+	key    string
+	logger *zap.Logger
+}
+
+// NewConsulConfigSource creates a ConfigSource backed by the Consul KV
+// key at key, talking to the Consul agent at addr (host:port).
+func NewConsulConfigSource(addr, key string, logger *zap.Logger) (*ConsulConfigSource, error) {
+	cfg := consulapi.DefaultConfig()
+	if addr != "" {
+		cfg.Address = addr
+	}
+
+	client, err := consulapi.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create consul client: %w", err)
+	}
+	return &ConsulConfigSource{client: client, key: key, logger: logger}, nil
+}
+
+// Load fetches and parses the value at s.key.
+func (s *ConsulConfigSource) Load(ctx context.Context) (*Config, error) {
+	config, _, err := s.fetch(ctx, 0)
+	return config, err
+}
+
+// fetch performs a blocking query against s.key, waiting for its
+// ModifyIndex to advance past waitIndex.
+func (s *ConsulConfigSource) fetch(ctx context.Context, waitIndex uint64) (*Config, uint64, error) {
+	opts := (&consulapi.QueryOptions{WaitIndex: waitIndex}).WithContext(ctx)
+	pair, meta, err := s.client.KV().Get(s.key, opts)
+	if err != nil {
+		return nil, waitIndex, fmt.Errorf("get %q: %w", s.key, err)
+	}
+	if pair == nil {
+		return nil, waitIndex, fmt.Errorf("key %q not found", s.key)
+	}
+
+	config, err := parseConfig(pair.Value)
+	if err != nil {
+		return nil, waitIndex, err
+	}
+	return config, meta.LastIndex, nil
+}
+
+// Watch repeatedly issues blocking queries against s.key, emitting a
+// new Config whenever its ModifyIndex advances.
+func (s *ConsulConfigSource) Watch(ctx context.Context) (<-chan *Config, error) {
+	configs := make(chan *Config)
+	go func() {
+		defer close(configs)
+
+		var waitIndex uint64
+		for {
+			config, idx, err := s.fetch(ctx, waitIndex)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				s.logger.Error("Failed to watch Consul key, keeping previous configuration",
+					zap.String("key", s.key), zap.Error(err))
+				continue
+			}
+			waitIndex = idx
+
+			select {
+			case configs <- config:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return configs, nil
+}
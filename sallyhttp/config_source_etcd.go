@@ -0,0 +1,78 @@
+package sallyhttp
+
+import (
+	"context"
+	"fmt"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// EtcdConfigSource loads a Config from a key in etcd and watches it for
+// changes using etcd's native watch API.
+type EtcdConfigSource struct {
+	client *clientv3.Client
+	key    string
+	logger *zap.Logger
+}
+
+// NewEtcdConfigSource creates a ConfigSource backed by the etcd key at
+// key, talking to the etcd member at addr (host:port).
+func NewEtcdConfigSource(addr, key string, logger *zap.Logger) (*EtcdConfigSource, error) {
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{addr}})
+	if err != nil {
+		return nil, fmt.Errorf("create etcd client: %w", err)
+	}
+	return &EtcdConfigSource{client: client, key: key, logger: logger}, nil
+}
+
+// Load fetches and parses the value at s.key.
+func (s *EtcdConfigSource) Load(ctx context.Context) (*Config, error) {
+	resp, err := s.client.Get(ctx, s.key)
+	if err != nil {
+		return nil, fmt.Errorf("get %q: %w", s.key, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("key %q not found", s.key)
+	}
+	return parseConfig(resp.Kvs[0].Value)
+}
+
+// Watch subscribes to etcd's watch stream for s.key, emitting a new
+// Config for every PUT event.
+func (s *EtcdConfigSource) Watch(ctx context.Context) (<-chan *Config, error) {
+	watchCh := s.client.Watch(ctx, s.key)
+	configs := make(chan *Config)
+
+	go func() {
+		defer close(configs)
+
+		for resp := range watchCh {
+			if err := resp.Err(); err != nil {
+				s.logger.Error("Error watching etcd key, keeping previous configuration",
+					zap.String("key", s.key), zap.Error(err))
+				continue
+			}
+
+			for _, ev := range resp.Events {
+				if ev.Type != clientv3.EventTypePut {
+					continue
+				}
+
+				config, err := parseConfig(ev.Kv.Value)
+				if err != nil {
+					s.logger.Error("Failed to parse updated configuration, keeping previous configuration",
+						zap.String("key", s.key), zap.Error(err))
+					continue
+				}
+
+				select {
+				case configs <- config:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return configs, nil
+}
@@ -0,0 +1,100 @@
+package sallyhttp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// FileConfigSource loads a Config from a local YAML file and watches it
+// for changes on disk (via fsnotify) and on SIGHUP.
+type FileConfigSource struct {
+	path   string
+	logger *zap.Logger
+}
+
+// NewFileConfigSource creates a ConfigSource that reads the YAML file at
+// path.
+func NewFileConfigSource(path string, logger *zap.Logger) *FileConfigSource {
+	return &FileConfigSource{path: path, logger: logger}
+}
+
+// Load reads and parses the file at s.path.
+func (s *FileConfigSource) Load(context.Context) (*Config, error) {
+	return Parse(s.path)
+}
+
+// Watch watches the directory containing s.path for changes, so that
+// config-map style mounts that replace the file (rather than writing in
+// place) are picked up, in addition to reacting to SIGHUP.
+func (s *FileConfigSource) Watch(ctx context.Context) (<-chan *Config, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create file watcher: %w", err)
+	}
+	if err := fsWatcher.Add(filepath.Dir(s.path)); err != nil {
+		fsWatcher.Close()
+		return nil, fmt.Errorf("watch %q: %w", s.path, err)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+
+	configs := make(chan *Config)
+	go func() {
+		defer close(configs)
+		defer fsWatcher.Close()
+		defer signal.Stop(sigCh)
+
+		path := filepath.Clean(s.path)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-sigCh:
+				s.reload(ctx, configs)
+
+			case event, ok := <-fsWatcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				s.reload(ctx, configs)
+
+			case err, ok := <-fsWatcher.Errors:
+				if !ok {
+					return
+				}
+				s.logger.Error("Error watching configuration file",
+					zap.String("path", s.path), zap.Error(err))
+			}
+		}
+	}()
+	return configs, nil
+}
+
+func (s *FileConfigSource) reload(ctx context.Context, configs chan<- *Config) {
+	config, err := s.Load(ctx)
+	if err != nil {
+		s.logger.Error("Failed to reload configuration, keeping previous configuration",
+			zap.String("path", s.path), zap.Error(err))
+		return
+	}
+
+	select {
+	case configs <- config:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,65 @@
+package sallyhttp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const _testConfigYAML = "url: https://example.com\npackages:\n  mytools:\n    repo: https://github.com/example/mytools\n"
+
+func TestFileConfigSource_Load(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sally.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(_testConfigYAML), 0o600))
+
+	src := NewFileConfigSource(path, zap.NewNop())
+	config, err := src.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", config.URL)
+	assert.Contains(t, config.Packages, "mytools")
+}
+
+func TestFileConfigSource_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sally.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(_testConfigYAML), 0o600))
+
+	src := NewFileConfigSource(path, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configs, err := src.Watch(ctx)
+	require.NoError(t, err)
+
+	t.Run("file write", func(t *testing.T) {
+		updated := _testConfigYAML + "  other:\n    repo: https://github.com/example/other\n"
+		require.NoError(t, os.WriteFile(path, []byte(updated), 0o600))
+
+		select {
+		case config := <-configs:
+			assert.Contains(t, config.Packages, "other")
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for reload after file write")
+		}
+	})
+
+	t.Run("SIGHUP", func(t *testing.T) {
+		require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+
+		select {
+		case config := <-configs:
+			assert.Contains(t, config.Packages, "mytools")
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for reload after SIGHUP")
+		}
+	})
+}
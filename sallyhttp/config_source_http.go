@@ -0,0 +1,113 @@
+package sallyhttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// _httpPollInterval is how often HTTPConfigSource polls its URL for
+// changes while watching.
+const _httpPollInterval = 30 * time.Second
+
+// HTTPConfigSource loads a Config from an HTTP(S) URL and polls it for
+// changes using conditional GETs keyed on the response ETag.
+type HTTPConfigSource struct {
+	url    string
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewHTTPConfigSource creates a ConfigSource that fetches YAML from url.
+func NewHTTPConfigSource(url string, logger *zap.Logger) *HTTPConfigSource {
+	return &HTTPConfigSource{
+		url:    url,
+		client: http.DefaultClient,
+		logger: logger,
+	}
+}
+
+// Load fetches and parses the configuration at s.url.
+func (s *HTTPConfigSource) Load(ctx context.Context) (*Config, error) {
+	config, _, err := s.fetch(ctx, "")
+	return config, err
+}
+
+// fetch performs a conditional GET of s.url, sending If-None-Match when
+// etag is non-empty. It returns (nil, etag, nil) for a 304 response.
+func (s *HTTPConfigSource) fetch(ctx context.Context, etag string) (*Config, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("build request: %w", err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch %q: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetch %q: unexpected status %s", s.url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("read %q: %w", s.url, err)
+	}
+
+	config, err := parseConfig(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return config, resp.Header.Get("ETag"), nil
+}
+
+// Watch polls s.url on a fixed interval, emitting a new Config whenever
+// the ETag changes.
+func (s *HTTPConfigSource) Watch(ctx context.Context) (<-chan *Config, error) {
+	configs := make(chan *Config)
+	go func() {
+		defer close(configs)
+
+		var etag string
+		ticker := time.NewTicker(_httpPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				config, newETag, err := s.fetch(ctx, etag)
+				if err != nil {
+					s.logger.Error("Failed to poll configuration, keeping previous configuration",
+						zap.String("url", s.url), zap.Error(err))
+					continue
+				}
+				if config == nil {
+					continue // not modified
+				}
+				etag = newETag
+
+				select {
+				case configs <- config:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return configs, nil
+}
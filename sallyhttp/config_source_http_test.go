@@ -0,0 +1,67 @@
+package sallyhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHTTPConfigSource_Load(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(_testConfigYAML))
+	}))
+	defer srv.Close()
+
+	src := NewHTTPConfigSource(srv.URL, zap.NewNop())
+	config, err := src.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", config.URL)
+	assert.Contains(t, config.Packages, "mytools")
+}
+
+func TestHTTPConfigSource_fetchNotModified(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(_testConfigYAML))
+	}))
+	defer srv.Close()
+
+	src := NewHTTPConfigSource(srv.URL, zap.NewNop())
+
+	config, etag, err := src.fetch(context.Background(), "")
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Equal(t, `"v1"`, etag)
+
+	config, etag, err = src.fetch(context.Background(), etag)
+	require.NoError(t, err)
+	assert.Nil(t, config)
+	assert.Equal(t, `"v1"`, etag)
+}
+
+func TestHTTPConfigSource_fetchError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	src := NewHTTPConfigSource(srv.URL, zap.NewNop())
+	_, err := src.Load(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,28 @@
+package sallyhttp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePatterns_literalShadowsPattern(t *testing.T) {
+	t.Parallel()
+
+	err := validatePatterns(map[string]Package{
+		"mytools":                {},
+		"mytools/{user}/{repo}": {},
+	})
+	assert.ErrorContains(t, err, `package "mytools" shadows pattern "mytools/{user}/{repo}"`)
+}
+
+func TestValidatePatterns_noShadow(t *testing.T) {
+	t.Parallel()
+
+	err := validatePatterns(map[string]Package{
+		"mytools/static/path":   {},
+		"mytools/{user}/{repo}": {},
+		"docs":                  {},
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,60 @@
+package sallyhttp
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ConfigWatcher drives reloads of a Handler's configuration in response
+// to whatever change notifications the ConfigSource it was created with
+// (via WithConfigSource) produces on its Watch channel. Reacting to
+// SIGHUP, where that's desired, is up to the ConfigSource itself - e.g.
+// FileConfigSource reloads on SIGHUP as well as on file-change events -
+// so that ConfigWatcher doesn't end up double-reloading alongside it.
+//
+// A failed reload - a parse error or a validation error - leaves the
+// handler's current configuration active; the error is logged and
+// watching continues.
+type ConfigWatcher struct {
+	handler *Handler
+	logger  *zap.Logger
+}
+
+// NewConfigWatcher creates a ConfigWatcher that reloads handler from
+// its ConfigSource whenever the source reports a change.
+//
+// handler is expected to already hold a snapshot from CreateHandler,
+// and to have been created with WithConfigSource; Watch only ever
+// replaces it.
+func NewConfigWatcher(handler *Handler, logger *zap.Logger) *ConfigWatcher {
+	return &ConfigWatcher{handler: handler, logger: logger}
+}
+
+// Watch blocks, reloading the configuration whenever the source reports
+// a change, until ctx is canceled.
+func (w *ConfigWatcher) Watch(ctx context.Context) error {
+	if w.handler.source == nil {
+		return fmt.Errorf("watch configuration source: handler has no configuration source; use WithConfigSource")
+	}
+
+	configs, err := w.handler.source.Watch(ctx)
+	if err != nil {
+		return fmt.Errorf("watch configuration source: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case config, ok := <-configs:
+			if !ok {
+				return nil
+			}
+			w.handler.Reload(config)
+			w.logger.Info("Reloaded configuration", zap.Object("config", config))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package sallyhttp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeConfigSource is a ConfigSource whose Watch channel is driven
+// directly by the test.
+type fakeConfigSource struct {
+	configs chan *Config
+}
+
+func (s *fakeConfigSource) Load(context.Context) (*Config, error) {
+	return nil, errors.New("Load not implemented by fakeConfigSource")
+}
+
+func (s *fakeConfigSource) Watch(context.Context) (<-chan *Config, error) {
+	return s.configs, nil
+}
+
+func TestConfigWatcher_Watch_reloadsOnChange(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeConfigSource{configs: make(chan *Config, 1)}
+	handler := CreateHandler(&Config{URL: "example.com"}, WithConfigSource(source))
+	watcher := NewConfigWatcher(handler, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- watcher.Watch(ctx) }()
+
+	updated := &Config{
+		URL:      "example.com",
+		Packages: map[string]Package{"mytools": {Repo: "https://github.com/example/mytools"}},
+	}
+	source.configs <- updated
+
+	require.Eventually(t, func() bool {
+		_, _, ok := handler.snapshot.Load().packages.Lookup("mytools")
+		return ok
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after ctx was canceled")
+	}
+}
+
+func TestConfigWatcher_Watch_noSource(t *testing.T) {
+	t.Parallel()
+
+	handler := CreateHandler(&Config{URL: "example.com"})
+	watcher := NewConfigWatcher(handler, zap.NewNop())
+
+	err := watcher.Watch(context.Background())
+	assert.ErrorContains(t, err, "no configuration source")
+}
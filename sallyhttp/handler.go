@@ -0,0 +1,311 @@
+package sallyhttp
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/sally/templates"
+	"go.uber.org/zap"
+)
+
+var (
+	_defaultIndexTemplate = template.Must(
+		template.New("index.html").Parse(templates.Index))
+	_defaultPackageTemplate = template.Must(
+		template.New("package.html").Parse(templates.Package))
+)
+
+// Option customizes the Handler returned by CreateHandler.
+type Option func(*handlerOptions)
+
+type handlerOptions struct {
+	logger        *zap.Logger
+	registerer    prometheus.Registerer
+	source        ConfigSource
+	indexTemplate *template.Template
+	pkgTemplate   *template.Template
+}
+
+// WithLogger sets the logger a Handler uses to report reload failures
+// and other operational events. Defaults to a no-op logger.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *handlerOptions) { o.logger = logger }
+}
+
+// WithMetrics registers a Handler's metrics with registerer instead of
+// the default Prometheus registry, so embedders can keep Sally's
+// metrics alongside their own.
+func WithMetrics(registerer prometheus.Registerer) Option {
+	return func(o *handlerOptions) { o.registerer = registerer }
+}
+
+// WithConfigSource attaches source to a Handler so that it can serve
+// its own "/-/reload" admin endpoint and be driven by a ConfigWatcher
+// without the caller needing to thread the source through separately.
+func WithConfigSource(source ConfigSource) Option {
+	return func(o *handlerOptions) { o.source = source }
+}
+
+// WithTemplates overrides the templates a Handler uses to render the
+// package index and the per-package godoc redirect pages, in place of
+// Sally's built-in templates.
+func WithTemplates(index, pkg *template.Template) Option {
+	return func(o *handlerOptions) {
+		o.indexTemplate = index
+		o.pkgTemplate = pkg
+	}
+}
+
+// CreateHandler creates a Sally http.Handler for config, customized by
+// opts.
+func CreateHandler(config *Config, opts ...Option) *Handler {
+	o := handlerOptions{
+		logger:        zap.NewNop(),
+		indexTemplate: _defaultIndexTemplate,
+		pkgTemplate:   _defaultPackageTemplate,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	h := &Handler{
+		logger:        o.logger,
+		registerer:    o.registerer,
+		source:        o.source,
+		indexTemplate: o.indexTemplate,
+		pkgTemplate:   o.pkgTemplate,
+		metrics:       newMetrics(o.registerer),
+	}
+	h.Reload(config)
+	return h
+}
+
+// configSnapshot holds a Config and the lookup trees built from it.
+// Handler swaps these in atomically so in-flight requests always
+// see a consistent config and pair of trees.
+type configSnapshot struct {
+	config   *Config
+	packages pathTree[Package]
+	params   paramTree[Package]
+}
+
+// buildSnapshot builds the literal and templated package trees for
+// config. config.Packages is assumed to have already been validated by
+// Parse, so pattern conflicts cannot occur here.
+func buildSnapshot(config *Config) *configSnapshot {
+	snap := &configSnapshot{config: config}
+	for name, pkg := range config.Packages {
+		if isPattern(name) {
+			if err := snap.params.Set(name, pkg); err != nil {
+				panic(err)
+			}
+			continue
+		}
+		snap.packages.Set(name, pkg)
+	}
+	return snap
+}
+
+// Handler serves vanity import-path redirects and godoc links for a
+// Config. It's created with CreateHandler and is safe for concurrent
+// use, including concurrent calls to Reload.
+type Handler struct {
+	logger        *zap.Logger
+	registerer    prometheus.Registerer
+	source        ConfigSource
+	indexTemplate *template.Template
+	pkgTemplate   *template.Template
+	metrics       *metrics
+
+	snapshot atomic.Pointer[configSnapshot]
+}
+
+// Reload atomically replaces the configuration served by h.
+// It is safe to call concurrently with ServeHTTP; in-flight requests
+// keep using the snapshot that was active when they started.
+func (h *Handler) Reload(config *Config) {
+	h.snapshot.Store(buildSnapshot(config))
+}
+
+// reloadFromSource re-reads and re-validates the configuration from
+// h.source, swapping it into h on success. It's a no-op, beyond
+// logging, if h.source is unset or the reload fails.
+func (h *Handler) reloadFromSource(ctx context.Context) {
+	if h.source == nil {
+		return
+	}
+
+	config, err := h.source.Load(ctx)
+	if err != nil {
+		h.logger.Error("Failed to reload configuration, keeping previous configuration", zap.Error(err))
+		return
+	}
+
+	h.Reload(config)
+	h.logger.Info("Reloaded configuration", zap.Object("config", config))
+}
+
+// ReloadHandler returns an http.Handler for a "/-/reload" admin
+// endpoint: a POST request reloads h's configuration from the
+// ConfigSource given via WithConfigSource. If the active configuration
+// sets Admin.ReloadToken, requests must present it as a bearer token.
+//
+// Returns a handler that reports 501 Not Implemented if h was created
+// without WithConfigSource.
+func (h *Handler) ReloadHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if h.source == nil {
+			http.Error(rw, "no configuration source configured", http.StatusNotImplemented)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if token := h.snapshot.Load().config.Admin.ReloadToken; token != "" {
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				http.Error(rw, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		h.reloadFromSource(r.Context())
+		rw.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// lookup resolves path against both the literal package tree and the
+// templated package patterns, preferring a literal match.
+// bindings is non-nil only when path matched a pattern.
+//
+// metricName identifies the matched package for metrics purposes: it's
+// pkgName for a literal match, but the unexpanded pattern (e.g.
+// "mytools/{user}/{repo}") for a templated match, so that metrics
+// cardinality stays bounded by the configured package set rather than
+// by the request-expanded path.
+func (snap *configSnapshot) lookup(path string) (pkgName, metricName string, pkg Package, bindings map[string]string, ok bool) {
+	if name, p, found := snap.packages.Lookup(path); found {
+		return name, name, p, nil, true
+	}
+
+	pattern, binds, p, found := snap.params.Match(path)
+	if !found {
+		return "", "", Package{}, nil, false
+	}
+
+	name, err := expandPattern(pattern, binds)
+	if err != nil {
+		return "", "", Package{}, nil, false
+	}
+	return name, pattern, p, binds, true
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	sw := &statusResponseWriter{ResponseWriter: w}
+	goGet := r.URL.Query().Get("go-get") == "1"
+	pkgName := _unmatchedPackageLabel
+	metricName := _unmatchedPackageLabel
+
+	defer func() {
+		h.metrics.observeRequest(metricName, sw.status, goGet)
+		h.logAccess(r, sw, pkgName, goGet, time.Since(start))
+	}()
+
+	snap := h.snapshot.Load()
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	name, metric, pkg, bindings, ok := snap.lookup(path)
+	h.metrics.observeLookup(ok)
+	if !ok {
+		pkgs := snap.packages.ListByPath(path)
+		if len(pkgs) == 0 {
+			if r.Method != http.MethodGet {
+				http.NotFound(sw, r)
+				return
+			}
+		}
+
+		var data struct {
+			URL      string
+			Packages map[string]Package
+			Godoc    struct{ Host string }
+		}
+
+		data.URL = snap.config.URL
+		data.Packages = pkgs
+		data.Godoc.Host = snap.config.Godoc.Host
+
+		h.metrics.indexRenders.Inc()
+		if err := h.executeTemplate(h.indexTemplate, _unmatchedPackageLabel, sw, data); err != nil {
+			http.Error(sw, err.Error(), 500)
+		}
+
+		return
+	}
+	pkgName = name
+	metricName = metric
+
+	// Extract the relative path to subpackages, if any.
+	//	"/foo/bar" => "/bar"
+	//	"/foo" => ""
+	relPath := strings.TrimPrefix(r.URL.Path, "/"+pkgName)
+
+	repo, err := renderBindings(pkg.Repo, bindings)
+	if err != nil {
+		http.Error(sw, err.Error(), 500)
+		return
+	}
+
+	baseURL := snap.config.URL
+	if pkg.URL != "" {
+		baseURL = pkg.URL
+	}
+	canonicalURL := fmt.Sprintf("%s/%s", baseURL, pkgName)
+
+	var data struct {
+		Repo         string
+		Branch       string
+		CanonicalURL string
+		GodocURL     string
+	}
+	data.Repo = repo
+	data.Branch = pkg.Branch
+	data.CanonicalURL = canonicalURL
+	data.GodocURL = fmt.Sprintf("https://%s/%s%s", snap.config.Godoc.Host, canonicalURL, relPath)
+	if err := h.executeTemplate(h.pkgTemplate, metricName, sw, data); err != nil {
+		http.Error(sw, err.Error(), 500)
+	}
+}
+
+// executeTemplate executes tmpl against w, recording its latency under
+// the "package" label metricName, which must be a bounded identifier
+// (a literal package name or an unexpanded pattern) rather than a
+// request-expanded path, to keep metrics cardinality bounded.
+func (h *Handler) executeTemplate(tmpl *template.Template, metricName string, w http.ResponseWriter, data any) error {
+	start := time.Now()
+	err := tmpl.Execute(w, data)
+	h.metrics.templateLatency.WithLabelValues(tmpl.Name(), metricName).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// logAccess emits a structured access log entry for a completed
+// request.
+func (h *Handler) logAccess(r *http.Request, sw *statusResponseWriter, pkgName string, goGet bool, latency time.Duration) {
+	h.logger.Info("Handled request",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.String("package", pkgName),
+		zap.Int("status", sw.status),
+		zap.Int("bytes", sw.bytes),
+		zap.Duration("latency", latency),
+		zap.String("userAgent", r.UserAgent()),
+		zap.Bool("goGet", goGet),
+	)
+}
@@ -0,0 +1,124 @@
+package sallyhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newTestHandler(opts ...Option) *Handler {
+	return CreateHandler(&Config{
+		URL: "example.com",
+		Packages: map[string]Package{
+			"mytools":                 {Repo: "https://github.com/example/mytools"},
+			"userrepos/{user}/{repo}": {Repo: "https://github.com/{user}/{repo}"},
+		},
+	}, opts...)
+}
+
+func TestHandler_ServeHTTP_literal(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/mytools?go-get=1", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "github.com/example/mytools")
+}
+
+func TestHandler_ServeHTTP_templated(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/userrepos/abhinav/sally?go-get=1", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "github.com/abhinav/sally")
+}
+
+func TestHandler_ServeHTTP_templatedSubpackage(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/userrepos/abhinav/sally/v2/subpkg?go-get=1", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "github.com/abhinav/sally")
+}
+
+func TestHandler_ServeHTTP_indexFallback(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "mytools")
+}
+
+func TestHandler_ServeHTTP_unmatchedNonGet(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/nope", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandler_ServeHTTP_metricsBoundedByPattern(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	h := newTestHandler(WithMetrics(registry))
+
+	for _, path := range []string{"/userrepos/abhinav/sally", "/userrepos/someone/else"} {
+		req := httptest.NewRequest(http.MethodGet, path+"?go-get=1", nil)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Both requests, despite expanding to different concrete paths,
+	// must collapse onto a single series keyed by the pattern itself.
+	got := testutil.ToFloat64(h.metrics.requestsTotal.WithLabelValues(
+		"userrepos/{user}/{repo}", "200", "true"))
+	assert.Equal(t, float64(2), got)
+}
+
+func TestHandler_ServeHTTP_logsAccess(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+	h := newTestHandler(WithLogger(zap.New(core)))
+
+	req := httptest.NewRequest(http.MethodGet, "/mytools?go-get=1", nil)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.FilterMessage("Handled request").All()
+	require.Len(t, entries, 1)
+
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "mytools", fields["package"])
+	assert.EqualValues(t, 200, fields["status"])
+	assert.Greater(t, fields["bytes"], int64(0))
+}
@@ -0,0 +1,77 @@
+package sallyhttp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// _unmatchedPackageLabel is the package label value used in place of
+// the actual requested path whenever a request doesn't match any
+// configured package, so that unrecognized paths can't inflate metrics
+// cardinality.
+const _unmatchedPackageLabel = "__unmatched__"
+
+// metrics holds the Prometheus collectors a Handler reports request and
+// rendering activity through.
+type metrics struct {
+	requestsTotal   *prometheus.CounterVec
+	lookupTotal     *prometheus.CounterVec
+	indexRenders    prometheus.Counter
+	templateLatency *prometheus.HistogramVec
+}
+
+// newMetrics creates a metrics and registers its collectors with
+// registerer, or the default Prometheus registry if registerer is nil.
+func newMetrics(registerer prometheus.Registerer) *metrics {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	m := &metrics{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sally_requests_total",
+			Help: "Total number of requests handled, by matched package and response status.",
+		}, []string{"package", "status", "go_get"}),
+		lookupTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sally_lookup_total",
+			Help: "Total number of package lookups, by whether the request matched a configured package.",
+		}, []string{"result"}),
+		indexRenders: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "sally_index_renders_total",
+			Help: "Total number of times the package index page was rendered.",
+		}),
+		templateLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "sally_template_duration_seconds",
+			Help: "Time spent executing a response template, by template and matched package.",
+		}, []string{"template", "package"}),
+	}
+
+	registerer.MustRegister(m.requestsTotal, m.lookupTotal, m.indexRenders, m.templateLatency)
+	return m
+}
+
+func (m *metrics) observeRequest(pkgName string, status int, goGet bool) {
+	m.requestsTotal.WithLabelValues(pkgName, strconv.Itoa(status), strconv.FormatBool(goGet)).Inc()
+}
+
+func (m *metrics) observeLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.lookupTotal.WithLabelValues(result).Inc()
+}
+
+// MetricsHandler returns an http.Handler for a "/metrics" endpoint
+// exposing h's Prometheus collectors, suitable for mounting on the
+// same mux as h or on a separate admin listener.
+func (h *Handler) MetricsHandler() http.Handler {
+	gatherer := prometheus.Gatherer(prometheus.DefaultGatherer)
+	if g, ok := h.registerer.(prometheus.Gatherer); ok {
+		gatherer = g
+	}
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
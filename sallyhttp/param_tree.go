@@ -0,0 +1,262 @@
+package sallyhttp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// segmentKind classifies a single '/'-separated segment of a package
+// path pattern.
+type segmentKind int
+
+const (
+	segmentLiteral segmentKind = iota
+	segmentParam
+	segmentCatchAll
+)
+
+// patternSegment is one compiled segment of a package path pattern such
+// as "mytools/{user}/{repo}".
+type patternSegment struct {
+	literal string // segment text, set for segmentLiteral
+	name    string // parameter name, set for segmentParam and segmentCatchAll
+	kind    segmentKind
+}
+
+// isPattern reports whether key is a parametric package path, e.g.
+// "mytools/{user}/{repo}", as opposed to a plain literal path.
+func isPattern(key string) bool {
+	return strings.Contains(key, "{")
+}
+
+// compilePattern splits a package path pattern into its segments,
+// classifying each one as a literal, a single-segment parameter
+// ("{name}"), or a multi-segment catch-all ("{name=**}").
+//
+// The catch-all segment, if present, must be the last segment.
+func compilePattern(pattern string) ([]patternSegment, error) {
+	parts := strings.Split(pattern, "/")
+	segs := make([]patternSegment, len(parts))
+	for i, part := range parts {
+		seg, err := compileSegment(part)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pattern, err)
+		}
+		if seg.kind == segmentCatchAll && i != len(parts)-1 {
+			return nil, fmt.Errorf("%q: catch-all parameter %q must be the last path segment", pattern, seg.name)
+		}
+		segs[i] = seg
+	}
+	return segs, nil
+}
+
+func compileSegment(part string) (patternSegment, error) {
+	if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+		return patternSegment{kind: segmentLiteral, literal: part}, nil
+	}
+
+	name := part[1 : len(part)-1]
+	if rest, ok := strings.CutSuffix(name, "=**"); ok {
+		if rest == "" {
+			return patternSegment{}, fmt.Errorf("catch-all parameter name must not be empty")
+		}
+		return patternSegment{kind: segmentCatchAll, name: rest}, nil
+	}
+
+	if name == "" {
+		return patternSegment{}, fmt.Errorf("parameter name must not be empty")
+	}
+	return patternSegment{kind: segmentParam, name: name}, nil
+}
+
+// expandPattern substitutes bound parameter values back into pattern,
+// producing the concrete path that a request matched, e.g.
+//
+//	expandPattern("mytools/{user}/{repo}", map[string]string{"user": "abhinav", "repo": "sally"})
+//	// => "mytools/abhinav/sally"
+func expandPattern(pattern string, bindings map[string]string) (string, error) {
+	segs, err := compilePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(segs))
+	for i, seg := range segs {
+		switch seg.kind {
+		case segmentLiteral:
+			parts[i] = seg.literal
+		case segmentParam, segmentCatchAll:
+			parts[i] = bindings[seg.name]
+		}
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+// _placeholderPattern matches "{name}" references inside a field such as
+// Package.Repo that should be expanded with bound parameter values.
+var _placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// renderBindings expands "{name}" placeholders in text with the given
+// parameter bindings using text/template, e.g. the Repo pattern
+// "https://github.com/{user}/{repo}" becomes
+// "https://github.com/abhinav/sally".
+func renderBindings(text string, bindings map[string]string) (string, error) {
+	if len(bindings) == 0 || !strings.Contains(text, "{") {
+		return text, nil
+	}
+
+	expanded := _placeholderPattern.ReplaceAllString(text, "{{.$1}}")
+	tmpl, err := template.New("").Parse(expanded)
+	if err != nil {
+		return "", fmt.Errorf("parse %q: %w", text, err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, bindings); err != nil {
+		return "", fmt.Errorf("render %q: %w", text, err)
+	}
+	return buf.String(), nil
+}
+
+// paramTree holds values keyed by templated path patterns
+// (e.g. "mytools/{user}/{repo}"), as a trie parallel to pathTree.
+//
+// Literal segments are matched first, followed by a single parameter
+// segment, followed by a catch-all segment, in that priority order.
+// Match backtracks into a lower-priority branch when a higher-priority
+// one fails to reach a value.
+//
+// The zero value of paramTree is safe for use.
+type paramTree[T any] struct {
+	root paramNode[T]
+}
+
+// paramNode is a single node in a paramTree. Don't use this directly.
+type paramNode[T any] struct {
+	literal map[string]*paramNode[T]
+
+	param     *paramNode[T]
+	paramName string
+
+	catchAll     *paramNode[T]
+	catchAllName string
+
+	// pattern is the original pattern registered for this node.
+	// Non-empty only if value is non-nil.
+	pattern string
+	value   *T
+}
+
+// Set registers value under pattern, a '/'-separated path pattern such
+// as "mytools/{user}/{repo}" or "mytools/{rest=**}".
+//
+// Set returns an error if pattern is malformed, or if it conflicts with
+// a previously registered pattern, e.g. two sibling segments
+// parameterized under different names.
+func (t *paramTree[T]) Set(pattern string, value T) error {
+	segs, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	n := &t.root
+	for _, seg := range segs {
+		switch seg.kind {
+		case segmentLiteral:
+			if n.literal == nil {
+				n.literal = make(map[string]*paramNode[T])
+			}
+			ch, ok := n.literal[seg.literal]
+			if !ok {
+				ch = &paramNode[T]{}
+				n.literal[seg.literal] = ch
+			}
+			n = ch
+
+		case segmentParam:
+			if n.param == nil {
+				n.param = &paramNode[T]{}
+				n.paramName = seg.name
+			} else if n.paramName != seg.name {
+				return fmt.Errorf("%q: parameter {%s} conflicts with sibling parameter {%s}",
+					pattern, seg.name, n.paramName)
+			}
+			n = n.param
+
+		case segmentCatchAll:
+			if n.catchAll == nil {
+				n.catchAll = &paramNode[T]{}
+				n.catchAllName = seg.name
+			} else if n.catchAllName != seg.name {
+				return fmt.Errorf("%q: catch-all {%s=**} conflicts with sibling catch-all {%s=**}",
+					pattern, seg.name, n.catchAllName)
+			}
+			n = n.catchAll
+		}
+	}
+
+	if n.value != nil {
+		return fmt.Errorf("%q: duplicate pattern %q", pattern, n.pattern)
+	}
+	n.pattern = pattern
+	n.value = &value
+	return nil
+}
+
+// Match finds the pattern that matches path, returning its value and
+// the parameter bindings produced along the way.
+//
+// As with pathTree.Lookup, a pattern matched by an ancestor of path
+// cascades down to all of that ancestor's descendants (e.g.
+// "mytools/{user}/{repo}" also matches
+// "mytools/abhinav/sally/v2/subpkg"), so that subpackages of a
+// templated package resolve the same way subpackages of a literal one
+// do.
+func (t *paramTree[T]) Match(path string) (pattern string, bindings map[string]string, value T, ok bool) {
+	bindings = make(map[string]string)
+	n := t.root.match(path, bindings)
+	if n == nil {
+		return "", nil, value, false
+	}
+	return n.pattern, bindings, *n.value, true
+}
+
+func (n *paramNode[T]) match(path string, bindings map[string]string) *paramNode[T] {
+	if path == "" {
+		if n.value != nil {
+			return n
+		}
+		return nil
+	}
+
+	head, tail := pathTakeFirst(path)
+
+	if ch, ok := n.literal[head]; ok {
+		if m := ch.match(tail, bindings); m != nil {
+			return m
+		}
+	}
+
+	if n.param != nil {
+		bindings[n.paramName] = head
+		if m := n.param.match(tail, bindings); m != nil {
+			return m
+		}
+		delete(bindings, n.paramName)
+	}
+
+	if n.catchAll != nil && n.catchAll.value != nil {
+		bindings[n.catchAllName] = path
+		return n.catchAll
+	}
+
+	// Nothing further down matched; n's own value, if any, cascades to
+	// every path beneath it.
+	if n.value != nil {
+		return n
+	}
+
+	return nil
+}
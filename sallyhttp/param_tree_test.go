@@ -0,0 +1,144 @@
+package sallyhttp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamTree_match(t *testing.T) {
+	t.Parallel()
+
+	var tree paramTree[string]
+	require.NoError(t, tree.Set("mytools/{user}/{repo}", "user-repo"))
+	require.NoError(t, tree.Set("mytools/static/path", "static"))
+	require.NoError(t, tree.Set("docs/{rest=**}", "docs"))
+
+	tests := []struct {
+		path         string
+		wantPattern  string
+		wantValue    string
+		wantBindings map[string]string
+	}{
+		{
+			path:        "mytools/abhinav/sally",
+			wantPattern: "mytools/{user}/{repo}",
+			wantValue:   "user-repo",
+			wantBindings: map[string]string{
+				"user": "abhinav",
+				"repo": "sally",
+			},
+		},
+		{
+			// Literal siblings take priority over the parameter branch.
+			path:         "mytools/static/path",
+			wantPattern:  "mytools/static/path",
+			wantValue:    "static",
+			wantBindings: map[string]string{},
+		},
+		{
+			path:        "docs/a/b/c",
+			wantPattern: "docs/{rest=**}",
+			wantValue:   "docs",
+			wantBindings: map[string]string{
+				"rest": "a/b/c",
+			},
+		},
+		{
+			// Subpackages of a templated package cascade to the same
+			// pattern, the way subpackages of a literal package
+			// cascade to it in pathTree.
+			path:        "mytools/abhinav/sally/v2/subpkg",
+			wantPattern: "mytools/{user}/{repo}",
+			wantValue:   "user-repo",
+			wantBindings: map[string]string{
+				"user": "abhinav",
+				"repo": "sally",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			pattern, bindings, value, ok := tree.Match(tt.path)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantPattern, pattern)
+			assert.Equal(t, tt.wantValue, value)
+			assert.Equal(t, tt.wantBindings, bindings)
+		})
+	}
+}
+
+func TestParamTree_noMatch(t *testing.T) {
+	t.Parallel()
+
+	var tree paramTree[string]
+	require.NoError(t, tree.Set("mytools/{user}/{repo}", "user-repo"))
+
+	_, _, _, ok := tree.Match("mytools/abhinav")
+	assert.False(t, ok)
+
+	_, _, _, ok = tree.Match("other/abhinav/sally")
+	assert.False(t, ok)
+}
+
+func TestParamTree_conflictingSiblingParams(t *testing.T) {
+	t.Parallel()
+
+	var tree paramTree[string]
+	require.NoError(t, tree.Set("mytools/{user}/repo", "a"))
+
+	err := tree.Set("mytools/{owner}/repo", "b")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicts with sibling parameter")
+}
+
+func TestParamTree_conflictingSiblingCatchAlls(t *testing.T) {
+	t.Parallel()
+
+	var tree paramTree[string]
+	require.NoError(t, tree.Set("mytools/{rest=**}", "a"))
+
+	err := tree.Set("mytools/{all=**}", "b")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicts with sibling catch-all")
+}
+
+func TestCompilePattern_catchAllMustBeLast(t *testing.T) {
+	t.Parallel()
+
+	_, err := compilePattern("mytools/{rest=**}/repo")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be the last path segment")
+}
+
+func TestExpandPattern(t *testing.T) {
+	t.Parallel()
+
+	got, err := expandPattern("mytools/{user}/{repo}", map[string]string{
+		"user": "abhinav",
+		"repo": "sally",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "mytools/abhinav/sally", got)
+}
+
+func TestRenderBindings(t *testing.T) {
+	t.Parallel()
+
+	got, err := renderBindings("https://github.com/{user}/{repo}", map[string]string{
+		"user": "abhinav",
+		"repo": "sally",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/abhinav/sally", got)
+}
+
+func TestRenderBindings_noPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	got, err := renderBindings("https://github.com/abhinav/sally", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/abhinav/sally", got)
+}
@@ -0,0 +1,314 @@
+package sallyhttp
+
+import (
+	"sort"
+	"strings"
+)
+
+// pathTree holds values in a tree-like hierarchy defined by /-separated paths
+// (e.g. import paths).
+// Values defined at a path cascade down to all descendants
+// unless a descendant has its own value specified.
+//
+// Internally, pathTree is a compressed radix trie over the raw path
+// string (httprouter-style): edges are labeled with byte-slices rather
+// than single path components, and common prefixes across inserted
+// paths are collapsed into shared edges. This keeps node counts and
+// lookup costs low for both deep, narrow configs (long chains of
+// single-child nodes collapse into one edge) and wide, shallow ones
+// (children are kept in a slice sorted by their label's first byte,
+// rather than a map).
+//
+// Cascading only ever applies at '/' boundaries in the path actually
+// being looked up, never partway through a path component - regardless
+// of where the trie itself happens to split edges.
+//
+// The zero value of pathTree is safe for use.
+type pathTree[T any] struct {
+	root pathTreeNode[T]
+
+	// Approximate number of nodes with explicit values.
+	// This is not exact.
+	// It is used to optimize the ListByPath method.
+	countHint int
+}
+
+// Set sets the value in the tree at the given path.
+// All descendants of the path will inherit the value
+// unless a value is set for them explicitly.
+func (t *pathTree[T]) Set(path string, value T) {
+	t.countHint++
+	t.root.insert(path, &value)
+}
+
+// Lookup retrieves the value for the given path.
+// If the path doesn't have an explicit value set,
+// the value for the closest ancestor with a value is returned.
+// If no value is set for the path or any of its ancestors,
+// this returns false.
+//
+// The returned path is the path for which a value was found.
+// It may be different from the path passed to Lookup.
+func (t *pathTree[T]) Lookup(path string) (found string, v T, ok bool) {
+	var last *pathTreeNode[T]
+
+	n := &t.root
+	pos := 0
+	for {
+		if n.value != nil && isPathBoundary(path, pos) {
+			last = n
+		}
+		if pos == len(path) {
+			break
+		}
+
+		idx := strings.IndexByte(n.indices, path[pos])
+		if idx < 0 {
+			break
+		}
+
+		child := n.children[idx]
+		common := commonPrefixLen(child.label, path[pos:])
+		if common < len(child.label) {
+			// The edge diverges from path before it's fully consumed;
+			// there's nothing further to descend into.
+			break
+		}
+
+		n = child
+		pos += common
+	}
+
+	if last == nil {
+		return found, v, false
+	}
+	return last.path, *last.value, true
+}
+
+// ListByPath returns a map of all values in the tree
+// that are descendants of the given path.
+// The returned map is keyed by the path for each value.
+func (t *pathTree[T]) ListByPath(path string) map[string]T {
+	n, filterChildren, ok := t.root.findSubtree(path)
+	if !ok {
+		return nil
+	}
+
+	items := make(map[string]T, t.countHint)
+	if n.value != nil {
+		items[n.path] = *n.value
+	}
+	for _, child := range n.children {
+		// A child whose label doesn't start a new '/' component merely
+		// extends the same path component as n (e.g. n is "foo" and
+		// child is "bar", forming "foobar") - it's not a descendant of
+		// path and must be excluded, along with everything beneath it.
+		if filterChildren && child.label[0] != '/' {
+			continue
+		}
+		child.collect(items)
+	}
+	return items
+}
+
+// pathTreeNode is a single node in a pathTree.
+// Don't use this directly.
+type pathTreeNode[T any] struct {
+	// label is the edge from this node's parent to this node.
+	// It may span multiple path components (e.g. "bar/baz") when
+	// nothing else in the tree branches off partway through, or a
+	// single component, or even part of one, if some other inserted
+	// path diverges partway through a component.
+	//
+	// Empty only for the root node.
+	label string
+
+	// Full path to this node from the root, i.e. the concatenation of
+	// every label from the root down to and including this node.
+	path string
+
+	// Value for this node.
+	// Non-nil only if this node has an explicit value assigned to it.
+	value *T
+
+	// Direct descendants of this node, sorted by the first byte of
+	// their label.
+	children []*pathTreeNode[T]
+
+	// indices[i] is the first byte of children[i].label.
+	// Kept in sync with children so it can be searched instead of
+	// scanning every child to find the one matching the next byte of a
+	// path being inserted or looked up.
+	indices string
+}
+
+// insert adds value at path, relative to this node, splitting and
+// adding edges as needed.
+//
+// Invariant: value must not be nil.
+func (n *pathTreeNode[T]) insert(path string, value *T) {
+	if len(path) == 0 {
+		n.value = value
+		return
+	}
+
+	idx := strings.IndexByte(n.indices, path[0])
+	if idx < 0 {
+		n.addChild(&pathTreeNode[T]{
+			label: path,
+			path:  n.path + path,
+			value: value,
+		})
+		return
+	}
+
+	child := n.children[idx]
+	common := commonPrefixLen(child.label, path)
+	if common < len(child.label) {
+		child.splitAt(common)
+	}
+
+	if rest := path[common:]; len(rest) > 0 {
+		child.insert(rest, value)
+	} else {
+		child.value = value
+	}
+}
+
+// splitAt splits this node's edge after its first `at` bytes, pushing
+// everything beyond that point (this node's value and children) down
+// into a new child node.
+func (n *pathTreeNode[T]) splitAt(at int) {
+	tail := &pathTreeNode[T]{
+		label:    n.label[at:],
+		path:     n.path,
+		value:    n.value,
+		children: n.children,
+		indices:  n.indices,
+	}
+
+	n.label = n.label[:at]
+	n.path = n.path[:len(n.path)-len(tail.label)]
+	n.value = nil
+	n.children = nil
+	n.indices = ""
+	n.addChild(tail)
+}
+
+// addChild inserts child among n's children, keeping children and
+// indices sorted by the child's first label byte.
+func (n *pathTreeNode[T]) addChild(child *pathTreeNode[T]) {
+	c := child.label[0]
+	i := sort.Search(len(n.indices), func(i int) bool { return n.indices[i] >= c })
+
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = child
+
+	n.indices = n.indices[:i] + string(c) + n.indices[i:]
+}
+
+// findSubtree locates the node whose full path is path, or, if path
+// ends partway through an edge's label, the child at the far end of
+// that edge - as long as path lands on a '/' boundary either way.
+//
+// The returned filterChildren reports whether the caller must still
+// exclude the returned node's direct children that don't start a new
+// '/' component: that's only the case when path was consumed exactly
+// up to a node whose own label may be extended, component-internally,
+// by further compressed edges (e.g. path "foo" landing on a node
+// whose child label is "bar", forming the unrelated "foobar"). When
+// path instead ends partway through an edge at a '/' boundary, or path
+// is empty, the returned node's entire subtree is already known-good.
+//
+// Returns ok=false if nothing in the tree is path or a descendant of
+// it.
+func (n *pathTreeNode[T]) findSubtree(path string) (node *pathTreeNode[T], filterChildren, ok bool) {
+	if path == "" {
+		return n, false, true
+	}
+
+	pos := 0
+	for pos < len(path) {
+		idx := strings.IndexByte(n.indices, path[pos])
+		if idx < 0 {
+			return nil, false, false
+		}
+
+		child := n.children[idx]
+		common := commonPrefixLen(child.label, path[pos:])
+		remaining := len(path) - pos
+
+		switch {
+		case common == len(child.label):
+			n = child
+			pos += common
+
+		case common == remaining:
+			// path ends partway through child's label; what's beyond
+			// it is a descendant only if it starts a new component.
+			if child.label[common] == '/' {
+				return child, false, true
+			}
+			return nil, false, false
+
+		default:
+			return nil, false, false
+		}
+	}
+	return n, true, true
+}
+
+// collect puts all values in the subtree rooted at this node into the
+// given map, keyed by path.
+func (n *pathTreeNode[T]) collect(items map[string]T) {
+	next := []*pathTreeNode[T]{n}
+	for len(next) > 0 {
+		// Treat the slice as a stack.
+		n := next[len(next)-1]
+		next = next[:len(next)-1]
+
+		if n.value != nil {
+			items[n.path] = *n.value
+		}
+
+		for _, ch := range n.children {
+			next = append(next, ch)
+		}
+	}
+}
+
+// isPathBoundary reports whether pos falls on a '/'-separated component
+// boundary in path: the very start, the very end, or right before a
+// '/'.
+func isPathBoundary(path string, pos int) bool {
+	return pos == 0 || pos == len(path) || path[pos] == '/'
+}
+
+// commonPrefixLen returns the length of the longest common byte prefix
+// of a and b.
+func commonPrefixLen(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+
+	i := 0
+	for i < max && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// Takes the first component of a path, returning it and the rest.
+//
+//	pathTakeFirst("foo/bar/baz")
+//	// => ("foo", "bar/baz")
+func pathTakeFirst(p string) (head, tail string) {
+	head, tail = p, ""
+	if idx := strings.IndexByte(p, '/'); idx >= 0 {
+		head, tail = p[:idx], p[idx+1:]
+	}
+
+	return head, tail
+}
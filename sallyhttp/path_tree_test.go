@@ -1,4 +1,4 @@
-package main
+package sallyhttp
 
 import (
 	"strconv"
@@ -234,6 +234,9 @@ func BenchmarkPathTreeDeep(b *testing.B) {
 	b.Run("1000", func(b *testing.B) {
 		benchmarkPathTreeDeep(b, 1000)
 	})
+	b.Run("10000", func(b *testing.B) {
+		benchmarkPathTreeDeep(b, 10000)
+	})
 }
 
 func benchmarkPathTreeDeep(b *testing.B, N int) {
@@ -294,6 +297,9 @@ func BenchmarkPathTreeWide(b *testing.B) {
 	b.Run("1000", func(b *testing.B) {
 		benchmarkPathTreeWide(b, 1000)
 	})
+	b.Run("10000", func(b *testing.B) {
+		benchmarkPathTreeWide(b, 10000)
+	})
 }
 
 func benchmarkPathTreeWide(b *testing.B, N int) {
@@ -0,0 +1,27 @@
+package sallyhttp
+
+import "net/http"
+
+// statusResponseWriter wraps an http.ResponseWriter to capture the
+// status code and number of bytes written to it, for access logging
+// and metrics.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
@@ -0,0 +1,36 @@
+package sallyhttp
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusResponseWriter(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	sw := &statusResponseWriter{ResponseWriter: rec}
+
+	sw.WriteHeader(201)
+	n, err := sw.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	assert.Equal(t, 201, sw.status)
+	assert.Equal(t, 5, sw.bytes)
+}
+
+func TestStatusResponseWriter_defaultStatus(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	sw := &statusResponseWriter{ResponseWriter: rec}
+
+	_, err := sw.Write([]byte("hello"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 200, sw.status)
+	assert.Equal(t, 5, sw.bytes)
+}